@@ -2,11 +2,15 @@ package override
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/influxdata/influxdb/v2/kit/feature"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -35,6 +39,96 @@ func Make(s string) (Flagger, error) {
 	}, nil
 }
 
+// MakeFromJSON builds a Flagger from a JSON object mapping flag key to
+// override value, e.g. {"flagKey": true, "otherFlag": 42}.
+func MakeFromJSON(b []byte) (Flagger, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return Flagger{}, fmt.Errorf("parsing JSON flag overrides: %w", err)
+	}
+
+	return Flagger{flags: stringifyValues(raw)}, nil
+}
+
+// MakeFromFile builds a Flagger from a file of flag overrides, dispatching
+// on its extension: ".json" is parsed as a JSON object, ".yaml"/".yml" as a
+// YAML mapping. Both produce the same key/value overrides as Make.
+func MakeFromFile(path string) (Flagger, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Flagger{}, fmt.Errorf("reading flag override file %q: %w", path, err)
+	}
+
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		return MakeFromJSON(b)
+	case ".yaml", ".yml":
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal(b, &raw); err != nil {
+			return Flagger{}, fmt.Errorf("parsing YAML flag override file %q: %w", path, err)
+		}
+		return Flagger{flags: stringifyValues(raw)}, nil
+	default:
+		return Flagger{}, fmt.Errorf("flag override file %q: unrecognized extension %q, want .json, .yaml, or .yml", path, ext)
+	}
+}
+
+// MakeFromEnv builds a Flagger from environment variables named
+// <prefix><FLAG_KEY>, where FLAG_KEY is the flag's key upper-cased with any
+// non-alphanumeric character replaced by an underscore - e.g. prefix
+// "INFLUX_FEATURE_" overrides flag "rateLimit" via
+// INFLUX_FEATURE_RATELIMIT. Only flags with a set environment variable are
+// included.
+func MakeFromEnv(prefix string) (Flagger, error) {
+	flags := make(map[string]string)
+	for _, flag := range feature.Flags() {
+		if v, ok := os.LookupEnv(prefix + envKey(flag.Key())); ok {
+			flags[flag.Key()] = v
+		}
+	}
+
+	return Flagger{flags: flags}, nil
+}
+
+// Merge layers sources into a single Flagger. Later sources take precedence
+// over earlier ones for any key they both define. The documented precedence
+// for influxd is env > file > CLI string > defaults, which callers get by
+// calling Merge(cli, file, env); Flags falls back to a flag's own default
+// for any key absent from every source.
+func Merge(sources ...Flagger) Flagger {
+	merged := make(map[string]string)
+	for _, s := range sources {
+		for k, v := range s.flags {
+			merged[k] = v
+		}
+	}
+
+	return Flagger{flags: merged}
+}
+
+func envKey(flagKey string) string {
+	var b strings.Builder
+	for _, r := range flagKey {
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - ('a' - 'A'))
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+func stringifyValues(raw map[string]interface{}) map[string]string {
+	m := make(map[string]string, len(raw))
+	for k, v := range raw {
+		m[k] = fmt.Sprintf("%v", v)
+	}
+	return m
+}
+
 func parse(s string) (map[string]string, error) {
 	var (
 		pairs = strings.Split(s, Comma)
@@ -58,42 +152,74 @@ func errMalformed(s string) error {
 	return fmt.Errorf("malformed configuration string %q must match format \"k1:v1,k2:v2,...\"", s)
 }
 
-// Flags returns a map of default values. It never returns an error.
+// Flags returns a map of default values, overridden by any source. If one or
+// more overrides fail to coerce or validate, it returns an aggregated error
+// listing every malformed key instead of failing on the first.
 func (f Flagger) Flags(_ context.Context, flags ...feature.Flag) (map[string]interface{}, error) {
 	if len(flags) == 0 {
 		flags = feature.Flags()
 	}
 
 	m := make(map[string]interface{}, len(flags))
+	var errs []string
 	for _, flag := range flags {
-		if s, overridden := f.flags[flag.Key()]; overridden {
-			iface, err := f.coerce(s, flag)
-			if err != nil {
-				return nil, err
-			}
-			m[flag.Key()] = iface
-		} else {
+		s, overridden := f.flags[flag.Key()]
+		if !overridden {
 			m[flag.Key()] = flag.Default()
+			continue
+		}
+
+		iface, err := coerceAndValidate(s, flag)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
 		}
+		m[flag.Key()] = iface
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid flag overrides:\n\t%s", strings.Join(errs, "\n\t"))
 	}
 
 	return m, nil
 }
 
-func (Flagger) coerce(s string, flag feature.Flag) (iface interface{}, err error) {
-	switch flag.(type) {
+// coerceAndValidate converts s to the Go type flag expects and validates it
+// against any bounds the flag declares: an IntFlag's Min/Max (when set to a
+// non-zero range) and, for a FloatFlag marked RolloutPercentage, the 0-1
+// range that a rollout percentage must fall in.
+func coerceAndValidate(s string, flag feature.Flag) (interface{}, error) {
+	switch f := flag.(type) {
 	case feature.BoolFlag:
-		iface, err = strconv.ParseBool(s)
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("flag %q: %q is not a valid bool: %v", flag.Key(), s, err)
+		}
+		return v, nil
+
 	case feature.IntFlag:
-		iface, err = strconv.Atoi(s)
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("flag %q: %q is not a valid int: %v", flag.Key(), s, err)
+		}
+		if f.Min != 0 || f.Max != 0 {
+			if v < f.Min || v > f.Max {
+				return nil, fmt.Errorf("flag %q: %d is outside the allowed range [%d, %d]", flag.Key(), v, f.Min, f.Max)
+			}
+		}
+		return v, nil
+
 	case feature.FloatFlag:
-		iface, err = strconv.ParseFloat(s, 64)
-	default:
-		iface = s
-	}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("flag %q: %q is not a valid float: %v", flag.Key(), s, err)
+		}
+		if f.RolloutPercentage && (v < 0 || v > 1) {
+			return nil, fmt.Errorf("flag %q: rollout percentage %v must be between 0 and 1", flag.Key(), v)
+		}
+		return v, nil
 
-	if err != nil {
-		return nil, fmt.Errorf("coercing string %q based on flag type %T: %v", s, flag, err)
+	default:
+		return s, nil
 	}
-	return
 }
@@ -0,0 +1,246 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"go.uber.org/zap"
+)
+
+// AuditReason identifies why an AuditEvent was emitted.
+type AuditReason string
+
+const (
+	AuditReasonCreated AuditReason = "created"
+	AuditReasonUpdated AuditReason = "updated"
+	AuditReasonDeleted AuditReason = "deleted"
+	AuditReasonUsed    AuditReason = "used"
+)
+
+// AuditEvent records a single authorization lifecycle or usage event.
+type AuditEvent struct {
+	// Sequence increases monotonically across every event emitted in this
+	// process, regardless of sink, so consumers can detect gaps.
+	Sequence   uint64
+	Reason     AuditReason
+	AuthID     influxdb.ID
+	OrgID      influxdb.ID
+	UserID     influxdb.ID
+	RemoteAddr string
+}
+
+// AuditSink receives AuditEvents as a Store creates, updates, deletes, or
+// uses authorizations.
+type AuditSink interface {
+	Audit(AuditEvent)
+}
+
+var auditSeq uint64
+
+func nextAuditSequence() uint64 {
+	return atomic.AddUint64(&auditSeq, 1)
+}
+
+func newAuditEvent(ctx context.Context, reason AuditReason, a *influxdb.Authorization) AuditEvent {
+	return AuditEvent{
+		Sequence:   nextAuditSequence(),
+		Reason:     reason,
+		AuthID:     a.ID,
+		OrgID:      a.OrgID,
+		UserID:     a.UserID,
+		RemoteAddr: remoteAddrFromContext(ctx),
+	}
+}
+
+// NoopAuditSink discards every event. It is the default sink until
+// SetAuditSink is called.
+type NoopAuditSink struct{}
+
+// Audit implements AuditSink.
+func (NoopAuditSink) Audit(AuditEvent) {}
+
+// ZapAuditSink logs every event at info level.
+type ZapAuditSink struct {
+	Log *zap.Logger
+}
+
+// Audit implements AuditSink.
+func (z ZapAuditSink) Audit(e AuditEvent) {
+	z.Log.Info("authorization audit event",
+		zap.Uint64("sequence", e.Sequence),
+		zap.String("reason", string(e.Reason)),
+		zap.Stringer("authID", e.AuthID),
+		zap.Stringer("orgID", e.OrgID),
+		zap.Stringer("userID", e.UserID),
+		zap.String("remoteAddr", e.RemoteAddr),
+	)
+}
+
+// ChannelAuditSink publishes every event to Events. Sends are non-blocking:
+// if Events is full, the event is dropped rather than stalling the
+// transaction that produced it.
+type ChannelAuditSink struct {
+	Events chan AuditEvent
+}
+
+// NewChannelAuditSink returns a ChannelAuditSink whose Events channel has
+// the given buffer size.
+func NewChannelAuditSink(buffer int) *ChannelAuditSink {
+	return &ChannelAuditSink{Events: make(chan AuditEvent, buffer)}
+}
+
+// Audit implements AuditSink.
+func (c *ChannelAuditSink) Audit(e AuditEvent) {
+	select {
+	case c.Events <- e:
+	default:
+	}
+}
+
+// auditSinkHolder lets s.auditSink hold an AuditSink (an interface value)
+// behind an atomic.Pointer, since atomic.Pointer[AuditSink] can't swap the
+// interface value itself atomically.
+type auditSinkHolder struct {
+	sink AuditSink
+}
+
+// SetAuditSink overrides the sink that s publishes authorization lifecycle
+// and usage events to. The default is NoopAuditSink. Safe to call
+// concurrently with in-flight audits.
+func (s *Store) SetAuditSink(sink AuditSink) {
+	s.auditSink.Store(&auditSinkHolder{sink: sink})
+}
+
+func (s *Store) audit(ctx context.Context, reason AuditReason, a *influxdb.Authorization) {
+	sink := AuditSink(NoopAuditSink{})
+	if h := s.auditSink.Load(); h != nil {
+		sink = h.sink
+	}
+	sink.Audit(newAuditEvent(ctx, reason, a))
+}
+
+type remoteAddrKey struct{}
+
+// WithRemoteAddr returns a context carrying addr, so that the audit events
+// emitted from calls made with it record the caller's remote address.
+func WithRemoteAddr(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, remoteAddrKey{}, addr)
+}
+
+func remoteAddrFromContext(ctx context.Context) string {
+	addr, _ := ctx.Value(remoteAddrKey{}).(string)
+	return addr
+}
+
+// RecordUsage is called by GetAuthorizationByToken on every successful
+// lookup. It enqueues a's last-used timestamp with the configured usage
+// debouncer, if any, and emits an AuditReasonUsed event.
+func (s *Store) RecordUsage(ctx context.Context, a *influxdb.Authorization) {
+	if d := s.usageDebouncer.Load(); d != nil {
+		d.record(a.ID, time.Now())
+	}
+	s.audit(ctx, AuditReasonUsed, a)
+}
+
+// SetUsageDebouncer installs d as the writer RecordUsage enqueues last-used
+// timestamps with. Pass nil to stop persisting last-used timestamps. Safe to
+// call concurrently with in-flight RecordUsage calls.
+func (s *Store) SetUsageDebouncer(d *usageDebouncer) {
+	s.usageDebouncer.Store(d)
+}
+
+// usageDebouncer batches last-used timestamp writes so that the hot
+// GetAuthorizationByToken path doesn't take a KV write transaction on every
+// call; it instead buffers updates in memory and flushes them together on
+// an interval.
+type usageDebouncer struct {
+	store    *Store
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[influxdb.ID]time.Time
+}
+
+// NewUsageDebouncer returns a usageDebouncer that flushes batched last-used
+// timestamps for s's authorizations every interval. Call Run in its own
+// goroutine to start it, and SetUsageDebouncer to wire it up to RecordUsage.
+func (s *Store) NewUsageDebouncer(interval time.Duration) *usageDebouncer {
+	return &usageDebouncer{
+		store:    s,
+		interval: interval,
+		pending:  make(map[influxdb.ID]time.Time),
+	}
+}
+
+func (d *usageDebouncer) record(id influxdb.ID, at time.Time) {
+	d.mu.Lock()
+	d.pending[id] = at
+	d.mu.Unlock()
+}
+
+// Run flushes batched last-used timestamps every Interval until ctx is
+// canceled, then flushes once more before returning.
+func (d *usageDebouncer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return d.flush(context.Background())
+		case <-ticker.C:
+			if err := d.flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *usageDebouncer) flush(ctx context.Context) error {
+	d.mu.Lock()
+	pending := d.pending
+	d.pending = make(map[influxdb.ID]time.Time)
+	d.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return d.store.applyUsageBatch(ctx, pending)
+}
+
+// applyUsageBatch writes the last-used timestamp for each pending
+// authorization in a single transaction.
+func (s *Store) applyUsageBatch(ctx context.Context, pending map[influxdb.ID]time.Time) error {
+	return s.kvStore.Update(ctx, func(tx kv.Tx) error {
+		for id, at := range pending {
+			a, encodedID, prefix, hashedSecret, err := s.getAuthRecord(ctx, tx, id)
+			if err == ErrAuthNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			a.LastUsedAt = at
+			v, err := encodeAuthorization(a, prefix, hashedSecret)
+			if err != nil {
+				return err
+			}
+
+			b, err := tx.Bucket(authBucket)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(encodedID, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
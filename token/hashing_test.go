@@ -0,0 +1,49 @@
+package token
+
+import "testing"
+
+func TestGenerateAuthToken(t *testing.T) {
+	token, prefix, secret, err := generateAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prefix) != authTokenPrefixLen {
+		t.Fatalf("expected prefix of length %d, got %q (%d)", authTokenPrefixLen, prefix, len(prefix))
+	}
+	if token != prefix+secret {
+		t.Fatalf("expected token to be prefix+secret, got token=%q prefix=%q secret=%q", token, prefix, secret)
+	}
+
+	gotPrefix, gotSecret, ok := splitPresentedToken(token)
+	if !ok {
+		t.Fatalf("splitPresentedToken rejected a freshly generated token %q", token)
+	}
+	if gotPrefix != prefix || gotSecret != secret {
+		t.Fatalf("splitPresentedToken did not reproduce the generated prefix/secret: got (%q, %q), want (%q, %q)", gotPrefix, gotSecret, prefix, secret)
+	}
+}
+
+func TestSplitPresentedToken_RejectsShortTokens(t *testing.T) {
+	for _, token := range []string{"", "infl_", "infl_1234"} {
+		if _, _, ok := splitPresentedToken(token); ok {
+			t.Fatalf("expected splitPresentedToken(%q) to reject a too-short token", token)
+		}
+	}
+}
+
+func TestHashAndCompareSecret(t *testing.T) {
+	cfg := AuthorizationHashingConfig{Cost: 4, Pepper: []byte("pepper")}
+
+	hashed, err := hashSecret("s3cret", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !compareSecret(hashed, "s3cret", cfg) {
+		t.Fatal("expected the original secret to compare equal to its own hash")
+	}
+	if compareSecret(hashed, "wrong", cfg) {
+		t.Fatal("expected a wrong secret not to compare equal")
+	}
+}
@@ -52,13 +52,61 @@ func initAuthorizationService(f itesting.AuthorizationFields, t *testing.T) (inf
 		}
 	}
 
+	sink := token.NewChannelAuditSink(len(f.Authorizations) + 2)
+	storage.SetAuditSink(sink)
+	t.Cleanup(func() { storage.SetAuditSink(token.NoopAuditSink{}) })
+
 	for _, u := range f.Authorizations {
 		if err := svc.CreateAuthorization(ctx, u); err != nil {
 			t.Fatalf("failed to populate authorizations")
 		}
+
+		select {
+		case evt := <-sink.Events:
+			if evt.Reason != token.AuditReasonCreated || evt.AuthID != u.ID {
+				t.Fatalf("expected a created audit event for %s, got %+v", u.ID, evt)
+			}
+		default:
+			t.Fatalf("expected a created audit event for %s", u.ID)
+		}
+	}
+
+	// Round-trip one throwaway authorization through create and delete, to
+	// assert the audit wiring emits both events with the expected shape -
+	// independent of whatever create/delete calls the conformance suite
+	// itself goes on to make through the returned client.
+	if len(f.Orgs) > 0 {
+		probe := &influxdb.Authorization{OrgID: f.Orgs[0].ID}
+		if len(f.Users) > 0 {
+			probe.UserID = f.Users[0].ID
+		}
+
+		if err := svc.CreateAuthorization(ctx, probe); err != nil {
+			t.Fatalf("failed to create probe authorization: %v", err)
+		}
+		select {
+		case evt := <-sink.Events:
+			if evt.Reason != token.AuditReasonCreated || evt.AuthID != probe.ID {
+				t.Fatalf("expected a created audit event for probe authorization %s, got %+v", probe.ID, evt)
+			}
+		default:
+			t.Fatalf("expected a created audit event for probe authorization %s", probe.ID)
+		}
+
+		if err := svc.DeleteAuthorization(ctx, probe.ID); err != nil {
+			t.Fatalf("failed to delete probe authorization: %v", err)
+		}
+		select {
+		case evt := <-sink.Events:
+			if evt.Reason != token.AuditReasonDeleted || evt.AuthID != probe.ID {
+				t.Fatalf("expected a deleted audit event for probe authorization %s, got %+v", probe.ID, evt)
+			}
+		default:
+			t.Fatalf("expected a deleted audit event for probe authorization %s", probe.ID)
+		}
 	}
 
-	handler := token.NewHTTPAuthHandler(zaptest.NewLogger(t), svc), tenant
+	handler := token.NewHTTPAuthHandler(zaptest.NewLogger(t), svc, ts)
 	r := chi.NewRouter()
 	r.Mount(handler.Prefix(), handler)
 	server := httptest.NewServer(r)
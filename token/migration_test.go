@@ -0,0 +1,94 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	influxdb "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// TestInitializeAuths_MigratesLegacyRecord writes a record in the pre-hashing
+// on-disk shape - a raw token and no prefix/hashedSecret - directly into
+// authBucket, then checks that initializeAuths rewrites it in place into a
+// prefix/hash pair that is still usable via the original token string, and
+// that a wrong secret against the migrated record is rejected.
+func TestInitializeAuths_MigratesLegacyRecord(t *testing.T) {
+	SetAuthorizationHashingConfig(AuthorizationHashingConfig{Cost: bcrypt.MinCost})
+
+	ctx := context.Background()
+	kvStore := inmem.NewKVStore()
+
+	store, err := NewStore(kvStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var id influxdb.ID = 1
+	encodedID, err := id.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const legacyToken = "legacytoken0123456789abcdef"
+	legacy := influxdb.Authorization{
+		ID:     id,
+		OrgID:  2,
+		UserID: 3,
+		Token:  legacyToken,
+		Status: influxdb.Active,
+	}
+
+	if err := kvStore.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(authBucket)
+		if err != nil {
+			return err
+		}
+		v, err := json.Marshal(authRecord{Authorization: legacy})
+		if err != nil {
+			return err
+		}
+		if err := b.Put(encodedID, v); err != nil {
+			return err
+		}
+
+		idx, err := authIndexBucket(tx)
+		if err != nil {
+			return err
+		}
+		return idx.Put(authIndexKey(legacyToken), encodedID)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kvStore.Update(ctx, func(tx kv.Tx) error {
+		return store.initializeAuths(ctx, tx)
+	}); err != nil {
+		t.Fatalf("initializeAuths failed to migrate the legacy record: %v", err)
+	}
+
+	if err := kvStore.View(ctx, func(tx kv.Tx) error {
+		a, err := store.GetAuthorizationByToken(ctx, tx, legacyToken)
+		if err != nil {
+			return err
+		}
+		if a.ID != id {
+			t.Fatalf("expected the migrated record to resolve to id %s, got %s", id, a.ID)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("migrated legacy record is not usable via its original token: %v", err)
+	}
+
+	wrongToken := legacyToken[:len(legacyToken)-1] + "0"
+	if err := kvStore.View(ctx, func(tx kv.Tx) error {
+		_, err := store.GetAuthorizationByToken(ctx, tx, wrongToken)
+		return err
+	}); err != ErrAuthNotFound {
+		t.Fatalf("expected a wrong secret against the migrated record to be rejected with ErrAuthNotFound, got %v", err)
+	}
+}
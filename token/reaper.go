@@ -0,0 +1,79 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+// ReaperConfig controls how often a Reaper looks for expired authorizations
+// and how many it deletes per write transaction.
+type ReaperConfig struct {
+	// Interval is how often the reaper scans for expired authorizations.
+	Interval time.Duration
+	// BatchSize caps how many expired authorizations are deleted in a
+	// single write transaction, so a reaper catching up after a long pause
+	// doesn't hold a write transaction open for an extended period.
+	BatchSize int
+}
+
+// DefaultReaperConfig is a reasonable default for production deployments.
+func DefaultReaperConfig() ReaperConfig {
+	return ReaperConfig{
+		Interval:  5 * time.Minute,
+		BatchSize: 1000,
+	}
+}
+
+// Reaper periodically deletes expired authorizations from a Store.
+type Reaper struct {
+	store  *Store
+	config ReaperConfig
+}
+
+// NewReaper returns a Reaper that deletes expired authorizations from s on
+// the schedule described by cfg. Callers are responsible for running it,
+// typically via `go reaper.Run(ctx)`.
+func (s *Store) NewReaper(cfg ReaperConfig) *Reaper {
+	return &Reaper{store: s, config: cfg}
+}
+
+// Run deletes expired authorizations every Interval until ctx is canceled.
+// It is meant to be started in its own goroutine.
+func (r *Reaper) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := r.RunOnce(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RunOnce performs a single reap pass: up to BatchSize expired
+// authorizations are found and deleted in one write transaction. It is
+// exported separately from Run so tests can drive the reaper deterministically
+// instead of waiting on the ticker.
+func (r *Reaper) RunOnce(ctx context.Context) error {
+	return r.store.kvStore.Update(ctx, func(tx kv.Tx) error {
+		expired, err := r.store.findExpiredAuthorizations(ctx, tx, r.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range expired {
+			if err := r.store.DeleteAuthorization(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -1,16 +1,41 @@
 package token
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"time"
 
-	"github.com/buger/jsonparser"
 	influxdb "github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kv"
 	jsonp "github.com/influxdata/influxdb/v2/pkg/jsonparser"
 )
 
+// authByOrgIndex and authByUserIndex hold composite <ownerID><authID> keys so
+// that ListAuthorizations can seek directly to the authorizations owned by a
+// given org or user, instead of scanning and decoding every record in
+// authBucket.
+var (
+	authByOrgIndex  = []byte("authorizationsbyorgindexv1")
+	authByUserIndex = []byte("authorizationsbyuserindexv1")
+)
+
+// ErrAuthExpired is returned in place of ErrAuthNotFound when an
+// authorization is otherwise valid but its ExpiresAt has passed, so callers
+// can distinguish "never existed" from "expired" if they need to.
+var ErrAuthExpired = &influxdb.Error{
+	Code: influxdb.EForbidden,
+	Msg:  "authorization has expired",
+}
+
+// ErrAuthNotYetValid is returned in place of ErrAuthNotFound when an
+// authorization is otherwise valid but its NotBefore has not yet passed.
+var ErrAuthNotYetValid = &influxdb.Error{
+	Code: influxdb.EForbidden,
+	Msg:  "authorization is not yet valid",
+}
+
 func authIndexKey(n string) []byte {
 	return []byte(n)
 }
@@ -24,17 +49,192 @@ func authIndexBucket(tx kv.Tx) (kv.Bucket, error) {
 	return b, nil
 }
 
+func authByOrgIndexBucket(tx kv.Tx) (kv.Bucket, error) {
+	b, err := tx.Bucket(authByOrgIndex)
+	if err != nil {
+		return nil, UnexpectedAuthIndexError(err)
+	}
+
+	return b, nil
+}
+
+func authByUserIndexBucket(tx kv.Tx) (kv.Bucket, error) {
+	b, err := tx.Bucket(authByUserIndex)
+	if err != nil {
+		return nil, UnexpectedAuthIndexError(err)
+	}
+
+	return b, nil
+}
+
+// authOwnerIndexKey builds the composite key used by authByOrgIndex and
+// authByUserIndex: the owning org or user ID followed by the authorization's
+// own ID, so a prefix seek on the owner ID alone returns every authorization
+// it owns.
+func authOwnerIndexKey(ownerID, authID influxdb.ID) ([]byte, error) {
+	ownerKey, err := ownerID.Encode()
+	if err != nil {
+		return nil, err
+	}
+	authKey, err := authID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 0, len(ownerKey)+len(authKey))
+	key = append(key, ownerKey...)
+	key = append(key, authKey...)
+	return key, nil
+}
+
+// putAuthOwnerIndexes writes (or overwrites) a's entries in authByOrgIndex
+// and, if it has one, authByUserIndex.
+func putAuthOwnerIndexes(tx kv.Tx, a *influxdb.Authorization, encodedID []byte) error {
+	orgIdx, err := authByOrgIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+	orgKey, err := authOwnerIndexKey(a.OrgID, a.ID)
+	if err != nil {
+		return ErrInvalidAuthIDError(err)
+	}
+	if err := orgIdx.Put(orgKey, encodedID); err != nil {
+		return ErrInternalServiceError(err)
+	}
+
+	if !a.UserID.Valid() {
+		return nil
+	}
+
+	userIdx, err := authByUserIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+	userKey, err := authOwnerIndexKey(a.UserID, a.ID)
+	if err != nil {
+		return ErrInvalidAuthIDError(err)
+	}
+	if err := userIdx.Put(userKey, encodedID); err != nil {
+		return ErrInternalServiceError(err)
+	}
+	return nil
+}
+
+// deleteAuthOwnerIndexes removes a's entries from authByOrgIndex and, if it
+// has one, authByUserIndex.
+func deleteAuthOwnerIndexes(tx kv.Tx, a *influxdb.Authorization) error {
+	orgIdx, err := authByOrgIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+	orgKey, err := authOwnerIndexKey(a.OrgID, a.ID)
+	if err != nil {
+		return ErrInvalidAuthIDError(err)
+	}
+	if err := orgIdx.Delete(orgKey); err != nil {
+		return ErrInternalServiceError(err)
+	}
+
+	if !a.UserID.Valid() {
+		return nil
+	}
+
+	userIdx, err := authByUserIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+	userKey, err := authOwnerIndexKey(a.UserID, a.ID)
+	if err != nil {
+		return ErrInvalidAuthIDError(err)
+	}
+	if err := userIdx.Delete(userKey); err != nil {
+		return ErrInternalServiceError(err)
+	}
+	return nil
+}
+
+// buildAuthOwnerIndexesIfEmpty is a one-shot index builder: if
+// authByOrgIndex is empty, it walks every record in authBucket and populates
+// both owner indexes, so that upgrading to a build with this index doesn't
+// require a re-import.
+func (s *Store) buildAuthOwnerIndexesIfEmpty(ctx context.Context, tx kv.Tx) error {
+	orgIdx, err := authByOrgIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	cur, err := orgIdx.Cursor()
+	if err != nil {
+		return err
+	}
+	if k, _ := cur.First(); k != nil {
+		return nil
+	}
+
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return err
+	}
+	bcur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := bcur.First(); k != nil; k, v = bcur.Next() {
+		a := &influxdb.Authorization{}
+		if _, _, err := decodeAuthorization(v, a); err != nil {
+			return err
+		}
+		if err := putAuthOwnerIndexes(tx, a, k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// initializeAuths opens (creating if necessary) the auth buckets, then
+// migrates any legacy records - ones written before token hashing was
+// introduced, which still carry their raw token in place of a prefix/hash
+// pair - in place.
 func (s *Store) initializeAuths(ctx context.Context, tx kv.Tx) error {
-	if _, err := tx.Bucket(authBucket); err != nil {
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
 		return err
 	}
 	if _, err := authIndexBucket(tx); err != nil {
 		return err
 	}
-	return nil
+	if _, err := authByOrgIndexBucket(tx); err != nil {
+		return err
+	}
+	if _, err := authByUserIndexBucket(tx); err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		if err := s.migrateLegacyAuth(ctx, tx, k, v); err != nil {
+			return err
+		}
+	}
+
+	return s.buildAuthOwnerIndexesIfEmpty(ctx, tx)
+}
+
+// authRecord is the on-disk shape of an authorization. It embeds the public
+// Authorization but strips the raw token, replacing it with the prefix used
+// to index the record and a one-way hash of its secret.
+type authRecord struct {
+	influxdb.Authorization
+	Prefix       string `json:"prefix,omitempty"`
+	HashedSecret string `json:"hashedSecret,omitempty"`
 }
 
-func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
+func encodeAuthorization(a *influxdb.Authorization, prefix, hashedSecret string) ([]byte, error) {
 	switch a.Status {
 	case influxdb.Active, influxdb.Inactive:
 	case "":
@@ -46,17 +246,109 @@ func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(a)
+	cp := *a
+	cp.Token = ""
+	return json.Marshal(authRecord{Authorization: cp, Prefix: prefix, HashedSecret: hashedSecret})
 }
 
-func decodeAuthorization(b []byte, a *influxdb.Authorization) error {
-	if err := json.Unmarshal(b, a); err != nil {
-		return err
+// decodeAuthorization unmarshals a persisted record into a, returning the
+// prefix and hashed secret it was stored under. A legacy record - one
+// written before hashing was introduced - has no prefix or hash; it is
+// returned with both empty and a.Token left as the raw legacy token, for
+// migrateLegacyAuth to pick up.
+func decodeAuthorization(b []byte, a *influxdb.Authorization) (prefix, hashedSecret string, err error) {
+	var rec authRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return "", "", err
 	}
+
+	*a = rec.Authorization
 	if a.Status == "" {
 		a.Status = influxdb.Active
 	}
-	return nil
+
+	if rec.Prefix == "" {
+		return "", "", nil
+	}
+
+	a.Token = rec.Prefix
+	return rec.Prefix, rec.HashedSecret, nil
+}
+
+// expired reports whether a's ExpiresAt has passed. An authorization with a
+// zero ExpiresAt never expires.
+func expired(a *influxdb.Authorization) bool {
+	return !a.ExpiresAt.IsZero() && a.ExpiresAt.Before(time.Now())
+}
+
+// notYetValid reports whether a's NotBefore is still in the future. An
+// authorization with a zero NotBefore is valid immediately.
+func notYetValid(a *influxdb.Authorization) bool {
+	return !a.NotBefore.IsZero() && a.NotBefore.After(time.Now())
+}
+
+// findExpiredAuthorizations returns up to limit IDs of authorizations whose
+// ExpiresAt has passed, for the Reaper to delete in a single transaction.
+func (s *Store) findExpiredAuthorizations(ctx context.Context, tx kv.Tx, limit int) ([]influxdb.ID, error) {
+	var ids []influxdb.ID
+	_, err := s.forEachAuthorization(ctx, tx, nil, nil, func(a *influxdb.Authorization) bool {
+		if expired(a) {
+			ids = append(ids, a.ID)
+		}
+		return len(ids) < limit
+	})
+	return ids, err
+}
+
+// migrateLegacyAuth rewrites a single legacy record - one still carrying a
+// raw token rather than a prefix/hash pair - in place, splitting its
+// existing token on the same boundary splitPresentedToken uses so that
+// callers presenting the old token value keep working unchanged.
+func (s *Store) migrateLegacyAuth(ctx context.Context, tx kv.Tx, encodedID, v []byte) error {
+	a := &influxdb.Authorization{}
+	prefix, hashedSecret, err := decodeAuthorization(v, a)
+	if err != nil {
+		return err
+	}
+	if prefix != "" || hashedSecret != "" || a.Token == "" {
+		// already migrated, or not a record we know how to migrate
+		return nil
+	}
+
+	legacyToken := a.Token
+	legacyPrefix, secret, ok := splitPresentedToken(legacyToken)
+	if !ok {
+		// too short to have ever been a usable token; leave it alone rather
+		// than guess.
+		return nil
+	}
+
+	hashed, err := hashSecret(secret, authHashing)
+	if err != nil {
+		return err
+	}
+
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+	if err := idx.Delete(authIndexKey(legacyToken)); err != nil && !kv.IsNotFound(err) {
+		return err
+	}
+	if err := idx.Put(authIndexKey(legacyPrefix), encodedID); err != nil {
+		return err
+	}
+
+	nv, err := encodeAuthorization(a, legacyPrefix, hashed)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, nv)
 }
 
 // CreateAuthorization takes an Authorization object and saves it in storage using its token
@@ -70,7 +362,29 @@ func (s *Store) CreateAuthorization(ctx context.Context, tx kv.Tx, a *influxdb.A
 		a.ID = id
 	}
 
-	v, err := encodeAuthorization(a)
+	token, prefix, secret, err := generateAuthToken()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "generating authorization token",
+			Err:  err,
+		}
+	}
+
+	if err := s.uniqueAuthPrefix(ctx, tx, prefix); err != nil {
+		return err
+	}
+
+	hashedSecret, err := hashSecret(secret, authHashing)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "hashing authorization token",
+			Err:  err,
+		}
+	}
+
+	v, err := encodeAuthorization(a, prefix, hashedSecret)
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -88,16 +402,12 @@ func (s *Store) CreateAuthorization(ctx context.Context, tx kv.Tx, a *influxdb.A
 		return err
 	}
 
-	if err := s.uniqueAuthToken(ctx, tx, a); err != nil {
-		return err
-	}
-
 	b, err := tx.Bucket(authBucket)
 	if err != nil {
 		return err
 	}
 
-	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
+	if err := idx.Put(authIndexKey(prefix), encodedID); err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInternal,
 			Err:  err,
@@ -110,6 +420,16 @@ func (s *Store) CreateAuthorization(ctx context.Context, tx kv.Tx, a *influxdb.A
 		}
 	}
 
+	if err := putAuthOwnerIndexes(tx, a, encodedID); err != nil {
+		return err
+	}
+
+	// the full token is only ever available here, at creation time; from now
+	// on only its prefix and a one-way hash of its secret are persisted.
+	a.Token = token
+
+	s.audit(ctx, AuditReasonCreated, a)
+
 	return nil
 
 }
@@ -135,29 +455,49 @@ func (s *Store) GetAuthorizationByID(ctx context.Context, tx kv.Tx, id influxdb.
 		return nil, ErrInternalServiceError(err)
 	}
 
-	if err := decodeAuthorization(v, a); err != nil {
+	a = &influxdb.Authorization{}
+	if _, _, err := decodeAuthorization(v, a); err != nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Err:  err,
 		}
 	}
 
+	if expired(a) {
+		return nil, ErrAuthExpired
+	}
+	if notYetValid(a) {
+		return nil, ErrAuthNotYetValid
+	}
+
 	return a, nil
 }
 
+// GetAuthorizationByToken looks up an authorization by the full token
+// presented by a caller. The token is split into the prefix used to seek
+// authIndex and the secret compared, in constant time, against the stored
+// hash - the raw secret is never itself persisted or looked up directly.
 func (s *Store) GetAuthorizationByToken(ctx context.Context, tx kv.Tx, token string) (*influxdb.Authorization, error) {
+	prefix, secret, ok := splitPresentedToken(token)
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "malformed authorization token",
+		}
+	}
+
 	idx, err := authIndexBucket(tx)
 	if err != nil {
 		return nil, err
 	}
 
-	// use the token to look up the authorization's ID
-	idKey, err := idx.Get(authIndexKey(token))
+	// use the prefix to look up the authorization's ID
+	idKey, err := idx.Get(authIndexKey(prefix))
 	if kv.IsNotFound(err) {
-		return nil, &influxdb.Error{
-			Code: influxdb.ENotFound,
-			Msg:  "authorization not found",
-		}
+		return nil, ErrAuthNotFound
+	}
+	if err != nil {
+		return nil, ErrInternalServiceError(err)
 	}
 
 	var id influxdb.ID
@@ -168,38 +508,325 @@ func (s *Store) GetAuthorizationByToken(ctx context.Context, tx kv.Tx, token str
 		}
 	}
 
-	return s.GetAuthorizationByID(ctx, tx, id)
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidAuthID
+	}
+
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return nil, ErrInternalServiceError(err)
+	}
+
+	v, err := b.Get(encodedID)
+	if kv.IsNotFound(err) {
+		return nil, ErrAuthNotFound
+	}
+	if err != nil {
+		return nil, ErrInternalServiceError(err)
+	}
+
+	a := &influxdb.Authorization{}
+	_, hashedSecret, err := decodeAuthorization(v, a)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if hashedSecret == "" {
+		// not yet migrated by initializeAuths; fall back to comparing the
+		// raw legacy token directly.
+		if a.Token != token {
+			return nil, ErrAuthNotFound
+		}
+	} else if !compareSecret(hashedSecret, secret, authHashing) {
+		return nil, ErrAuthNotFound
+	}
+
+	if expired(a) {
+		return nil, ErrAuthExpired
+	}
+	if notYetValid(a) {
+		return nil, ErrAuthNotYetValid
+	}
+
+	s.RecordUsage(ctx, a)
+
+	return a, nil
 }
 
-// ListAuthorizations returns all the authorizations matching a set of FindOptions. This function is used for
-// FindAuthorizationByID, FindAuthorizationByToken, and FindAuthorizations in the AuthorizationService implementation
-func (s *Store) ListAuthorizations(ctx context.Context, tx kv.Tx, f influxdb.AuthorizationFilter) ([]*influxdb.Authorization, error) {
+// ListAuthorizations returns the authorizations matching f, honoring Limit,
+// Offset, and After on the (optional) FindOptions for pagination. It returns
+// the matching page along with the cursor for the next one, which is ""
+// when Limit is unset or the page came back with fewer than Limit results;
+// a full last page still returns a cursor, whose page is then empty. This
+// function is used for
+// FindAuthorizationByID, FindAuthorizationByToken, and FindAuthorizations in
+// the AuthorizationService implementation.
+func (s *Store) ListAuthorizations(ctx context.Context, tx kv.Tx, f influxdb.AuthorizationFilter, opts ...influxdb.FindOptions) ([]*influxdb.Authorization, string, error) {
+	var opt influxdb.FindOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	var startAfter []byte
+	if opt.After != nil {
+		ak, err := decodeAuthCursor(*opt.After)
+		if err != nil {
+			return nil, "", &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "malformed authorization cursor",
+				Err:  err,
+			}
+		}
+		startAfter = ak
+	}
+
+	// A Token filter names at most one authorization, and matching it
+	// requires the same prefix lookup plus constant-time hash comparison
+	// GetAuthorizationByToken already does - the stored record never holds
+	// the raw token to compare against directly, so scanning and decoding
+	// every record here would not even be correct, let alone cheap.
+	if f.Token != nil {
+		a, err := s.GetAuthorizationByToken(ctx, tx, *f.Token)
+		switch err {
+		case nil:
+			return []*influxdb.Authorization{a}, "", nil
+		case ErrAuthNotFound, ErrAuthExpired, ErrAuthNotYetValid:
+			return nil, "", nil
+		default:
+			return nil, "", err
+		}
+	}
+
+	// OrgID and UserID are selective enough, and common enough, to warrant
+	// seeking the owner indexes instead of decoding every record in
+	// authBucket just to check them.
+	if f.OrgID != nil {
+		return s.listAuthorizationsByOwner(ctx, tx, authByOrgIndex, *f.OrgID, f, startAfter, opt.Limit, opt.Offset)
+	}
+	if f.UserID != nil {
+		return s.listAuthorizationsByOwner(ctx, tx, authByUserIndex, *f.UserID, f, startAfter, opt.Limit, opt.Offset)
+	}
+
+	if _, err := tx.Bucket(authBucket); err != nil {
+		return nil, "", err
+	}
+
+	var as []*influxdb.Authorization
+	skipped := 0
+	pred := authorizationsPredicateFn(f)
+	filterFn := filterAuthorizationsFn(f)
+	lastKey, err := s.forEachAuthorization(ctx, tx, pred, startAfter, func(a *influxdb.Authorization) bool {
+		if expired(a) || notYetValid(a) || !filterFn(a) {
+			return true
+		}
+		if skipped < opt.Offset {
+			skipped++
+			return true
+		}
+		as = append(as, a)
+		return opt.Limit <= 0 || len(as) < opt.Limit
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return as, nextAuthCursor(as, lastKey, opt.Limit), nil
+}
+
+// forEachAuthorizationByOwner seeks indexBucket (authByOrgIndex or
+// authByUserIndex) on the prefix formed by ownerID and calls fn for every
+// authorization record it owns, in index order, starting after startAfter
+// (or from ownerID's prefix, if nil). It stops when fn returns false or the
+// owner's entries are exhausted, and returns the last key visited so
+// callers that paginate can turn it into a cursor. This is the owner-scoped
+// counterpart to forEachAuthorization, used by both ListAuthorizations and
+// StreamAuthorizations so neither has to fall back to a full authBucket
+// scan just because a caller asked for one org or user's tokens.
+func (s *Store) forEachAuthorizationByOwner(ctx context.Context, tx kv.Tx, indexBucket []byte, ownerID influxdb.ID, startAfter []byte, fn func(*influxdb.Authorization) bool) (lastKey []byte, err error) {
+	idx, err := tx.Bucket(indexBucket)
+	if err != nil {
+		return nil, UnexpectedAuthIndexError(err)
+	}
+
+	prefix, err := ownerID.Encode()
+	if err != nil {
+		return nil, ErrInvalidAuthIDError(err)
+	}
+
+	cur, err := idx.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
 	b, err := tx.Bucket(authBucket)
 	if err != nil {
 		return nil, err
 	}
 
+	var k, encodedID []byte
+	if startAfter != nil {
+		k, encodedID = cur.Seek(startAfter)
+		if k != nil && bytes.Equal(k, startAfter) {
+			k, encodedID = cur.Next()
+		}
+	} else {
+		k, encodedID = cur.Seek(prefix)
+	}
+
+	for ; k != nil && bytes.HasPrefix(k, prefix); k, encodedID = cur.Next() {
+		v, err := b.Get(encodedID)
+		if kv.IsNotFound(err) {
+			// owner index and authBucket briefly disagree across a delete;
+			// treat as not-yet-visible rather than failing the whole scan.
+			continue
+		}
+		if err != nil {
+			return nil, ErrInternalServiceError(err)
+		}
+
+		a := &influxdb.Authorization{}
+		if _, _, err := decodeAuthorization(v, a); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+
+		lastKey = k
+		if !fn(a) {
+			break
+		}
+	}
+
+	return lastKey, nil
+}
+
+// listAuthorizationsByOwner is the ListAuthorizations dispatch target for a
+// Org/UserID-filtered lookup: it honors the same pagination parameters as
+// ListAuthorizations, buffering a page instead of streaming it.
+func (s *Store) listAuthorizationsByOwner(ctx context.Context, tx kv.Tx, indexBucket []byte, ownerID influxdb.ID, f influxdb.AuthorizationFilter, startAfter []byte, limit, offset int) ([]*influxdb.Authorization, string, error) {
+	filterFn := filterAuthorizationsFn(f)
 	var as []*influxdb.Authorization
+	skipped := 0
+	lastKey, err := s.forEachAuthorizationByOwner(ctx, tx, indexBucket, ownerID, startAfter, func(a *influxdb.Authorization) bool {
+		if expired(a) || notYetValid(a) || !filterFn(a) {
+			return true
+		}
+		if skipped < offset {
+			skipped++
+			return true
+		}
+		as = append(as, a)
+		return limit <= 0 || len(as) < limit
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return as, nextAuthCursor(as, lastKey, limit), nil
+}
+
+// streamAuthorizationsByOwner is the StreamAuthorizations dispatch target
+// for an Org/UserID-filtered lookup, seeking indexBucket instead of falling
+// back to a full authBucket scan.
+func (s *Store) streamAuthorizationsByOwner(ctx context.Context, tx kv.Tx, indexBucket []byte, ownerID influxdb.ID, f influxdb.AuthorizationFilter, fn func(*influxdb.Authorization) error) error {
+	filterFn := filterAuthorizationsFn(f)
+	var streamErr error
+	_, err := s.forEachAuthorizationByOwner(ctx, tx, indexBucket, ownerID, nil, func(a *influxdb.Authorization) bool {
+		if expired(a) || notYetValid(a) || !filterFn(a) {
+			return true
+		}
+		if streamErr = fn(a); streamErr != nil {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return streamErr
+}
+
+// StreamAuthorizations calls fn for every authorization matching f in order,
+// stopping at the first error, without ever materializing the full result
+// set in memory - unlike ListAuthorizations, which buffers a page. Intended
+// for callers streaming a large result set out, e.g. as NDJSON over HTTP.
+func (s *Store) StreamAuthorizations(ctx context.Context, tx kv.Tx, f influxdb.AuthorizationFilter, fn func(*influxdb.Authorization) error) error {
+	if f.Token != nil {
+		a, err := s.GetAuthorizationByToken(ctx, tx, *f.Token)
+		switch err {
+		case nil:
+			return fn(a)
+		case ErrAuthNotFound, ErrAuthExpired, ErrAuthNotYetValid:
+			return nil
+		default:
+			return err
+		}
+	}
+
+	// Same rationale as ListAuthorizations: an Org/UserID filter is
+	// selective enough, and common enough, to warrant seeking the owner
+	// indexes instead of scanning and decoding every record in authBucket.
+	if f.OrgID != nil {
+		return s.streamAuthorizationsByOwner(ctx, tx, authByOrgIndex, *f.OrgID, f, fn)
+	}
+	if f.UserID != nil {
+		return s.streamAuthorizationsByOwner(ctx, tx, authByUserIndex, *f.UserID, f, fn)
+	}
+
 	pred := authorizationsPredicateFn(f)
 	filterFn := filterAuthorizationsFn(f)
-	err = s.forEachAuthorization(ctx, tx, pred, func(a *influxdb.Authorization) bool {
-		if filterFn(a) {
-			as = append(as, a)
+
+	var streamErr error
+	_, err := s.forEachAuthorization(ctx, tx, pred, nil, func(a *influxdb.Authorization) bool {
+		if expired(a) || notYetValid(a) || !filterFn(a) {
+			return true
+		}
+		if streamErr = fn(a); streamErr != nil {
+			return false
 		}
 		return true
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
+	return streamErr
+}
+
+// encodeAuthCursor turns the last raw key visited by a paginated scan into
+// the opaque cursor token handed back to callers.
+func encodeAuthCursor(lastKey []byte) string {
+	return base64.RawURLEncoding.EncodeToString(lastKey)
+}
 
-	return as, nil
+// decodeAuthCursor reverses encodeAuthCursor.
+func decodeAuthCursor(cursor string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(cursor)
+}
+
+// nextAuthCursor returns the cursor for the page following results, or ""
+// if results did not fill a full page (and so there is nothing more to
+// fetch).
+func nextAuthCursor(results []*influxdb.Authorization, lastKey []byte, limit int) string {
+	if limit <= 0 || len(results) < limit || lastKey == nil {
+		return ""
+	}
+	return encodeAuthCursor(lastKey)
 }
 
-// forEachAuthorization will iterate through all authorizations while fn returns true.
-func (s *Store) forEachAuthorization(ctx context.Context, tx kv.Tx, pred kv.CursorPredicateFunc, fn func(*influxdb.Authorization) bool) error {
+// forEachAuthorization iterates through authBucket while fn returns true,
+// starting after startAfter (or from the beginning, if startAfter is nil) so
+// that paginated callers can resume a scan with cur.Seek instead of always
+// restarting at First. It returns the last key visited, for the caller to
+// turn into a next-page cursor.
+func (s *Store) forEachAuthorization(ctx context.Context, tx kv.Tx, pred kv.CursorPredicateFunc, startAfter []byte, fn func(*influxdb.Authorization) bool) (lastKey []byte, err error) {
 	b, err := tx.Bucket(authBucket)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var cur kv.Cursor
@@ -209,45 +836,78 @@ func (s *Store) forEachAuthorization(ctx context.Context, tx kv.Tx, pred kv.Curs
 		cur, err = b.Cursor()
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+	var k, v []byte
+	if startAfter != nil {
+		k, v = cur.Seek(startAfter)
+		if k != nil && bytes.Equal(k, startAfter) {
+			k, v = cur.Next()
+		}
+	} else {
+		k, v = cur.First()
+	}
+
+	for ; k != nil; k, v = cur.Next() {
 		// preallocate Permissions to reduce multiple slice re-allocations
 		a := &influxdb.Authorization{
 			Permissions: make([]influxdb.Permission, 64),
 		}
 
-		if err := decodeAuthorization(v, a); err != nil {
-			return err
+		if _, _, err := decodeAuthorization(v, a); err != nil {
+			return nil, err
 		}
+		// record lastKey before invoking fn: fn returns false on the record
+		// that fills the caller's page, and that record's key must still be
+		// captured for the next-page cursor to be correct.
+		lastKey = k
 		if !fn(a) {
 			break
 		}
 	}
 
-	return nil
+	return lastKey, nil
 }
 
-// UpdateAuthorization updates the status and description only of an authorization
-func (s *Store) UpdateAuthorization(ctx context.Context, tx kv.Tx, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
-	a, err := s.GetAuthorizationByID(ctx, tx, id)
+// getAuthRecord fetches and decodes the record for id, along with the prefix
+// and hashed secret it is stored under, so that callers which need to
+// re-encode the record (UpdateAuthorization) or remove its index entry
+// (DeleteAuthorization) don't have to re-derive them.
+func (s *Store) getAuthRecord(ctx context.Context, tx kv.Tx, id influxdb.ID) (a *influxdb.Authorization, encodedID []byte, prefix, hashedSecret string, err error) {
+	encodedID, err = id.Encode()
 	if err != nil {
-		return nil, &influxdb.Error{
-			Code: influxdb.ENotFound,
-			Err:  err,
-		}
+		return nil, nil, "", "", ErrInvalidAuthID
 	}
 
-	v, err := encodeAuthorization(a)
+	b, err := tx.Bucket(authBucket)
 	if err != nil {
-		return nil, &influxdb.Error{
+		return nil, nil, "", "", ErrInternalServiceError(err)
+	}
+
+	v, err := b.Get(encodedID)
+	if kv.IsNotFound(err) {
+		return nil, nil, "", "", ErrAuthNotFound
+	}
+	if err != nil {
+		return nil, nil, "", "", ErrInternalServiceError(err)
+	}
+
+	a = &influxdb.Authorization{}
+	prefix, hashedSecret, err = decodeAuthorization(v, a)
+	if err != nil {
+		return nil, nil, "", "", &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Err:  err,
 		}
 	}
 
-	encodedID, err := a.ID.Encode()
+	return a, encodedID, prefix, hashedSecret, nil
+}
+
+// UpdateAuthorization updates the status, description, ExpiresAt and NotBefore only of an authorization
+func (s *Store) UpdateAuthorization(ctx context.Context, tx kv.Tx, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
+	a, encodedID, prefix, hashedSecret, err := s.getAuthRecord(ctx, tx, id)
 	if err != nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -261,15 +921,31 @@ func (s *Store) UpdateAuthorization(ctx context.Context, tx kv.Tx, id influxdb.I
 	if upd.Description != nil {
 		a.Description = *upd.Description
 	}
+	if upd.ExpiresAt != nil {
+		a.ExpiresAt = *upd.ExpiresAt
+	}
+	if upd.NotBefore != nil {
+		a.NotBefore = *upd.NotBefore
+	}
 
 	a.SetUpdatedAt(time.Now())
 
+	// the token itself is immutable once minted, so the prefix and hashed
+	// secret carry over unchanged.
+	v, err := encodeAuthorization(a, prefix, hashedSecret)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
 	idx, err := authIndexBucket(tx)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
+	if err := idx.Put(authIndexKey(prefix), encodedID); err != nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.EInternal,
 			Err:  err,
@@ -287,22 +963,25 @@ func (s *Store) UpdateAuthorization(ctx context.Context, tx kv.Tx, id influxdb.I
 		}
 	}
 
+	// org and user are immutable on update today, but re-put defensively so
+	// this stays correct if that ever changes.
+	if err := putAuthOwnerIndexes(tx, a, encodedID); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, AuditReasonUpdated, a)
+
 	return a, nil
 
 }
 
 // DeleteAuthorization removes an authorization from storage
 func (s *Store) DeleteAuthorization(ctx context.Context, tx kv.Tx, id influxdb.ID) error {
-	a, err := s.GetAuthorizationByID(ctx, tx, id)
+	a, encodedID, prefix, _, err := s.getAuthRecord(ctx, tx, id)
 	if err != nil {
 		return nil
 	}
 
-	encodedID, err := id.Encode()
-	if err != nil {
-		return ErrInvalidAuthID
-	}
-
 	idx, err := authIndexBucket(tx)
 	if err != nil {
 		return err
@@ -313,19 +992,29 @@ func (s *Store) DeleteAuthorization(ctx context.Context, tx kv.Tx, id influxdb.I
 		return err
 	}
 
-	if err := idx.Delete([]byte(a.Token)); err != nil {
+	if err := idx.Delete(authIndexKey(prefix)); err != nil {
 		return ErrInternalServiceError(err)
 	}
 
+	if err := deleteAuthOwnerIndexes(tx, a); err != nil {
+		return err
+	}
+
 	if err := b.Delete(encodedID); err != nil {
 		return ErrInternalServiceError(err)
 	}
 
+	s.audit(ctx, AuditReasonDeleted, a)
+
 	return nil
 }
 
-func (s *Store) uniqueAuthToken(ctx context.Context, tx kv.Tx, a *influxdb.Authorization) error {
-	err := unique(ctx, tx, authIndex, authIndexKey(a.Token))
+// uniqueAuthPrefix ensures the newly generated prefix for a token does not
+// already exist in authIndex. Prefix collisions are vanishingly unlikely
+// given authTokenPrefixLen, but are still worth guarding against explicitly
+// rather than silently overwriting another authorization's index entry.
+func (s *Store) uniqueAuthPrefix(ctx context.Context, tx kv.Tx, prefix string) error {
+	err := unique(ctx, tx, authIndex, authIndexKey(prefix))
 	if err == kv.NotUniqueError {
 		// by returning a generic error we are trying to hide when
 		// a token is non-unique.
@@ -372,17 +1061,10 @@ func authorizationsPredicateFn(f influxdb.AuthorizationFilter) kv.CursorPredicat
 		}
 	}
 
-	if f.Token != nil {
-		exp := *f.Token
-		return func(_, value []byte) bool {
-			// it is assumed that token never has escaped string data
-			got, _, _, err := jsonparser.Get(value, "token")
-			if err != nil {
-				return true
-			}
-			return string(got) == exp
-		}
-	}
+	// f.Token is handled by ListAuthorizations/StreamAuthorizations before
+	// either ever reaches here: the stored record never holds the raw
+	// token, so matching it means going through GetAuthorizationByToken's
+	// prefix lookup and hash comparison, not a predicate over raw JSON.
 
 	var pred kv.CursorPredicateFunc
 	if f.OrgID != nil {
@@ -417,11 +1099,7 @@ func filterAuthorizationsFn(filter influxdb.AuthorizationFilter) func(a *influxd
 		}
 	}
 
-	if filter.Token != nil {
-		return func(a *influxdb.Authorization) bool {
-			return a.Token == *filter.Token
-		}
-	}
+	// filter.Token, like above, is handled before this is ever reached.
 
 	// Filter by org and user
 	if filter.OrgID != nil && filter.UserID != nil {
@@ -0,0 +1,101 @@
+package token_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/token"
+)
+
+// TestListAuthorizations_StableOrderingAcrossOwnerIndex creates a batch of
+// authorizations for one org, deletes one out of the middle of the set in
+// its own transaction before any paging starts, and checks that paging
+// through ListAuthorizations with a
+// small page size and draining StreamAuthorizations both visit every
+// remaining authorization for that org exactly once, in the same order,
+// via the authByOrgIndex secondary index added for org/user-scoped lookups.
+func TestListAuthorizations_StableOrderingAcrossOwnerIndex(t *testing.T) {
+	ctx := context.Background()
+	kvStore := inmem.NewKVStore()
+
+	storage, err := token.NewStore(kvStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		orgID  influxdb.ID = 1
+		userID influxdb.ID = 2
+	)
+
+	const n = 9
+	ids := make([]influxdb.ID, 0, n)
+	if err := kvStore.Update(ctx, func(tx kv.Tx) error {
+		for i := 0; i < n; i++ {
+			a := &influxdb.Authorization{OrgID: orgID, UserID: userID}
+			if err := storage.CreateAuthorization(ctx, tx, a); err != nil {
+				return err
+			}
+			ids = append(ids, a.ID)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := kvStore.Update(ctx, func(tx kv.Tx) error {
+		return storage.DeleteAuthorization(ctx, tx, ids[n/2])
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var paged []influxdb.ID
+	var after *string
+	for {
+		var (
+			page   []*influxdb.Authorization
+			cursor string
+		)
+		if err := kvStore.View(ctx, func(tx kv.Tx) error {
+			opt := influxdb.FindOptions{Limit: 2, After: after}
+			var err error
+			page, cursor, err = storage.ListAuthorizations(ctx, tx, influxdb.AuthorizationFilter{OrgID: &orgID}, opt)
+			return err
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, a := range page {
+			paged = append(paged, a.ID)
+		}
+		if cursor == "" {
+			break
+		}
+		after = &cursor
+	}
+
+	var streamed []influxdb.ID
+	if err := kvStore.View(ctx, func(tx kv.Tx) error {
+		return storage.StreamAuthorizations(ctx, tx, influxdb.AuthorizationFilter{OrgID: &orgID}, func(a *influxdb.Authorization) error {
+			streamed = append(streamed, a.ID)
+			return nil
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paged) != n-1 {
+		t.Fatalf("expected %d paged authorizations, got %d", n-1, len(paged))
+	}
+	if len(streamed) != len(paged) {
+		t.Fatalf("paged and streamed result counts differ: %d vs %d", len(paged), len(streamed))
+	}
+	for i := range paged {
+		if paged[i] != streamed[i] {
+			t.Fatalf("paged and streamed order differ at index %d: %s vs %s", i, paged[i], streamed[i])
+		}
+	}
+}